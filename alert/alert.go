@@ -0,0 +1,34 @@
+// Package alert fires notifications when a running simulation's invariants
+// break, e.g. storage divergence across nodes or a node that's stopped
+// producing blocks. It's deliberately decoupled from the faults package
+// that detects those conditions, so the same sinks can be reused to alert
+// on anything else worth paging a human (or a dashboard) about.
+package alert
+
+import "fmt"
+
+// Sink delivers a single alert message somewhere.
+type Sink interface {
+	Send(msg string) error
+}
+
+// Alerter fans a single Alert call out to every configured Sink, logging
+// (rather than failing) if an individual sink errors, since one sink being
+// down shouldn't swallow an alert the others could still deliver.
+type Alerter struct {
+	sinks []Sink
+}
+
+// New creates an Alerter that delivers to every given sink.
+func New(sinks ...Sink) *Alerter {
+	return &Alerter{sinks: sinks}
+}
+
+// Alert delivers msg to every sink.
+func (a *Alerter) Alert(msg string) {
+	for _, sink := range a.sinks {
+		if err := sink.Send(msg); err != nil {
+			fmt.Printf("alert: sink failed to deliver %q: %s\n", msg, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package alert
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+)
+
+// StderrSink writes alerts to stderr. It's the default sink: it has no
+// external dependencies, so it always works even if every other sink's
+// transport is down.
+type StderrSink struct{}
+
+// Send implements Sink.
+func (StderrSink) Send(msg string) error {
+	_, err := fmt.Fprintln(os.Stderr, "ALERT:", msg)
+	return err
+}
+
+// WebhookSink POSTs the alert message as a JSON body to a webhook URL
+// (Slack-compatible incoming webhooks accept {"text": "..."}).
+type WebhookSink struct {
+	URL string
+}
+
+// Send implements Sink.
+func (w WebhookSink) Send(msg string) error {
+	body := []byte(`{"text":` + jsonString(msg) + `}`)
+
+	resp, err := http.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func jsonString(s string) string {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case '\n':
+			buf.WriteString(`\n`)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// SMTPSink emails the alert message via an SMTP relay.
+type SMTPSink struct {
+	Addr string // host:port
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+// Send implements Sink.
+func (s SMTPSink) Send(msg string) error {
+	body := []byte(fmt.Sprintf("Subject: gossamer-sim alert\r\n\r\n%s\r\n", msg))
+	return smtp.SendMail(s.Addr, s.Auth, s.From, s.To, body)
+}
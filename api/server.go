@@ -0,0 +1,227 @@
+// Package api exposes a simulation.Network over HTTP so that nodes can be
+// created, connected, and driven with extrinsics from outside the harness
+// process, following the same client/server split as go-ethereum's p2psim.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/noot/gossamer-demo-extrinsic/simulation"
+)
+
+// DefaultAddr is the address the server listens on when none is given.
+const DefaultAddr = "127.0.0.1:8549"
+
+// Server serves the control API for a single simulation.Network.
+type Server struct {
+	net *simulation.Network
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	nextPort int
+}
+
+// NewServer creates a Server driving net. New nodes get P2P ports counting
+// up from basePort and RPC ports counting up from basePort+1000.
+func NewServer(net *simulation.Network, basePort int) *Server {
+	s := &Server{
+		net:      net,
+		mux:      http.NewServeMux(),
+		nextPort: basePort,
+	}
+
+	s.mux.HandleFunc("/nodes", s.handleNodes)
+	s.mux.HandleFunc("/nodes/", s.handleNodeConn)
+	s.mux.HandleFunc("/extrinsic", s.handleExtrinsic)
+	s.mux.HandleFunc("/storage/", s.handleStorage)
+
+	return s
+}
+
+// ListenAndServe starts the HTTP server on addr. If addr is empty,
+// DefaultAddr is used.
+func (s *Server) ListenAndServe(addr string) error {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return http.Serve(lis, s.mux)
+}
+
+// createNodeRequest is the POST /nodes body.
+type createNodeRequest struct {
+	ID           string `json:"id"`
+	GossamerPath string `json:"gossamerPath"`
+	BasePath     string `json:"basePath"`
+	Genesis      string `json:"genesis"`
+	ConfigFile   string `json:"configFile"`
+	Bootnodes    string `json:"bootnodes"`
+}
+
+type nodeResponse struct {
+	ID      string `json:"id"`
+	RPCAddr string `json:"rpcAddr"`
+	P2PPort int    `json:"p2pPort"`
+}
+
+// handleNodes handles POST /nodes: create and start a new node.
+func (s *Server) handleNodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req createNodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.ID == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	p2pPort, rpcPort := s.allocatePorts()
+
+	cfg := &simulation.NodeConfig{
+		ID:           req.ID,
+		GossamerPath: req.GossamerPath,
+		BasePath:     req.BasePath,
+		Genesis:      req.Genesis,
+		ConfigFile:   req.ConfigFile,
+		Bootnodes:    req.Bootnodes,
+		P2PPort:      p2pPort,
+		RPCPort:      rpcPort,
+	}
+
+	node, err := s.net.NewNode(cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := node.Start(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, nodeResponse{ID: req.ID, RPCAddr: cfg.RPCAddr(), P2PPort: p2pPort})
+}
+
+// allocatePorts hands out a fresh (p2pPort, rpcPort) pair for a new node.
+func (s *Server) allocatePorts() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p2pPort := s.nextPort
+	rpcPort := s.nextPort + 1000
+	s.nextPort++
+	return p2pPort, rpcPort
+}
+
+// handleNodeConn handles POST /nodes/{id}/conn/{peer}.
+func (s *Server) handleNodeConn(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/nodes/"), "/"), "/")
+	if len(parts) != 3 || parts[1] != "conn" {
+		http.Error(w, "expected /nodes/{id}/conn/{peer}", http.StatusBadRequest)
+		return
+	}
+	id, peer := parts[0], parts[2]
+
+	if err := s.net.Connect(id, peer); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+type submitExtrinsicRequest struct {
+	Node string `json:"node"`
+	Hex  string `json:"hex"`
+}
+
+// handleExtrinsic handles POST /extrinsic: submit a hex-encoded extrinsic
+// to the given node via author_submitExtrinsic.
+func (s *Server) handleExtrinsic(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req submitExtrinsicRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	node, ok := s.net.Get(req.Node)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown node %s", req.Node), http.StatusNotFound)
+		return
+	}
+
+	hexVal := req.Hex
+	if !strings.HasPrefix(hexVal, "0x") {
+		hexVal = "0x" + hexVal
+	}
+
+	respBody, err := simulation.PostRPC("author_submitExtrinsic", node.Config().RPCAddr(), `"`+hexVal+`"`)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(respBody)
+}
+
+// handleStorage handles GET /storage/{key}?node={id}.
+func (s *Server) handleStorage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/storage/")
+	if key == "" {
+		http.Error(w, "key is required", http.StatusBadRequest)
+		return
+	}
+
+	nodeID := r.URL.Query().Get("node")
+	node, ok := s.net.Get(nodeID)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown node %s", nodeID), http.StatusNotFound)
+		return
+	}
+
+	if !strings.HasPrefix(key, "0x") {
+		key = "0x" + key
+	}
+
+	respBody, err := simulation.PostRPC("state_getStorage", node.Config().RPCAddr(), `["`+key+`"]`)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(respBody)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
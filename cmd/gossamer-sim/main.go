@@ -0,0 +1,216 @@
+// Command gossamer-sim drives a simulation.Network from outside the
+// process that owns it: `gossamer-sim serve` starts the control API, and
+// the remaining subcommands are a thin HTTP client for it, mirroring the
+// p2psim command-line client.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/noot/gossamer-demo-extrinsic/api"
+	"github.com/noot/gossamer-demo-extrinsic/simulation"
+)
+
+var addrFlag = cli.StringFlag{
+	Name:  "addr",
+	Usage: "address of the gossamer-sim control API",
+	Value: "http://" + api.DefaultAddr,
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "gossamer-sim"
+	app.Usage = "drive live gossamer extrinsic experiments"
+	app.Commands = []cli.Command{
+		serveCommand,
+		nodeCommand,
+		netCommand,
+		txCommand,
+		storageCommand,
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+var serveCommand = cli.Command{
+	Name:  "serve",
+	Usage: "start the control API server",
+	Flags: []cli.Flag{
+		cli.StringFlag{Name: "listen", Usage: "address to listen on", Value: api.DefaultAddr},
+		cli.IntFlag{Name: "base-port", Usage: "first port handed out to new nodes", Value: 7000},
+	},
+	Action: func(ctx *cli.Context) error {
+		net := simulation.NewNetwork(simulation.NewExecAdapter())
+		srv := api.NewServer(net, ctx.Int("base-port"))
+
+		fmt.Println("gossamer-sim listening on", ctx.String("listen"))
+		return srv.ListenAndServe(ctx.String("listen"))
+	},
+}
+
+var nodeCommand = cli.Command{
+	Name:  "node",
+	Usage: "manage simulation nodes",
+	Subcommands: []cli.Command{
+		{
+			Name:  "create",
+			Usage: "create and start a new node",
+			Flags: []cli.Flag{
+				addrFlag,
+				cli.StringFlag{Name: "id", Usage: "node key, e.g. alice"},
+				cli.StringFlag{Name: "gossamer-path", Usage: "path to gossamer binary"},
+				cli.StringFlag{Name: "basepath", Usage: "node basepath"},
+				cli.StringFlag{Name: "genesis", Usage: "path to genesis.json", Value: "genesis.json"},
+				cli.StringFlag{Name: "config", Usage: "path to config.toml", Value: "config.toml"},
+				cli.StringFlag{Name: "bootnodes", Usage: "bootnode multiaddr"},
+			},
+			Action: func(ctx *cli.Context) error {
+				id := ctx.String("id")
+				if id == "" {
+					return fmt.Errorf("--id is required")
+				}
+
+				body := map[string]string{
+					"id":           id,
+					"gossamerPath": ctx.String("gossamer-path"),
+					"basePath":     ctx.String("basepath"),
+					"genesis":      ctx.String("genesis"),
+					"configFile":   ctx.String("config"),
+					"bootnodes":    ctx.String("bootnodes"),
+				}
+
+				return postJSON(ctx.String(addrFlag.Name)+"/nodes", body)
+			},
+		},
+	},
+}
+
+var netCommand = cli.Command{
+	Name:  "net",
+	Usage: "manage node connectivity",
+	Subcommands: []cli.Command{
+		{
+			Name:  "connect",
+			Usage: "connect two nodes",
+			Flags: []cli.Flag{
+				addrFlag,
+				cli.StringFlag{Name: "from", Usage: "node id to dial from"},
+				cli.StringFlag{Name: "to", Usage: "node id to dial"},
+			},
+			Action: func(ctx *cli.Context) error {
+				from, to := ctx.String("from"), ctx.String("to")
+				if from == "" || to == "" {
+					return fmt.Errorf("--from and --to are required")
+				}
+
+				url := fmt.Sprintf("%s/nodes/%s/conn/%s", ctx.String(addrFlag.Name), from, to)
+				resp, err := http.Post(url, "application/json", nil)
+				if err != nil {
+					return err
+				}
+				defer resp.Body.Close()
+
+				return checkResponse(resp)
+			},
+		},
+	},
+}
+
+var txCommand = cli.Command{
+	Name:  "tx",
+	Usage: "submit extrinsics",
+	Subcommands: []cli.Command{
+		{
+			Name:  "submit",
+			Usage: "submit a hex-encoded extrinsic to a node",
+			Flags: []cli.Flag{
+				addrFlag,
+				cli.StringFlag{Name: "node", Usage: "node id to submit to"},
+				cli.StringFlag{Name: "hex", Usage: "hex-encoded extrinsic"},
+			},
+			Action: func(ctx *cli.Context) error {
+				node, hex := ctx.String("node"), ctx.String("hex")
+				if node == "" || hex == "" {
+					return fmt.Errorf("--node and --hex are required")
+				}
+
+				return postJSON(ctx.String(addrFlag.Name)+"/extrinsic", map[string]string{
+					"node": node,
+					"hex":  hex,
+				})
+			},
+		},
+	},
+}
+
+var storageCommand = cli.Command{
+	Name:  "storage",
+	Usage: "query node storage",
+	Subcommands: []cli.Command{
+		{
+			Name:  "get",
+			Usage: "get a storage value from a node",
+			Flags: []cli.Flag{
+				addrFlag,
+				cli.StringFlag{Name: "node", Usage: "node id to query"},
+				cli.StringFlag{Name: "key", Usage: "hex-encoded storage key"},
+			},
+			Action: func(ctx *cli.Context) error {
+				node, key := ctx.String("node"), ctx.String("key")
+				if node == "" || key == "" {
+					return fmt.Errorf("--node and --key are required")
+				}
+				key = strings.TrimPrefix(key, "0x")
+
+				url := fmt.Sprintf("%s/storage/%s?node=%s", ctx.String(addrFlag.Name), key, node)
+				resp, err := http.Get(url)
+				if err != nil {
+					return err
+				}
+				defer resp.Body.Close()
+
+				return checkResponse(resp)
+			},
+		},
+	},
+}
+
+func postJSON(url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkResponse(resp)
+}
+
+func checkResponse(resp *http.Response) error {
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed: %s", respBody)
+	}
+
+	fmt.Println(string(respBody))
+	return nil
+}
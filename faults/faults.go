@@ -0,0 +1,227 @@
+// Package faults injects failures into a running simulation.Network so the
+// demo can double as a chaos test for the gossamer node: killing/restarting
+// nodes, partitioning the network, delaying RPC responses, and corrupting
+// submitted extrinsics.
+package faults
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/noot/gossamer-demo-extrinsic/simulation"
+)
+
+// Config controls which faults an Injector injects, and how often.
+type Config struct {
+	// KillEvery, if nonzero, kills and restarts a random node on this
+	// interval.
+	KillEvery time.Duration
+
+	// PartitionEvery, if nonzero, splits the network into two halves on
+	// this interval, healing the previous partition first.
+	PartitionEvery time.Duration
+
+	// RPCDelay, if nonzero, is the latency added to every RPC response by
+	// the DelayProxy Start puts in front of each node. Callers that want
+	// the delay to apply must route their RPC traffic through RPCAddr
+	// instead of dialing the node directly.
+	RPCDelay time.Duration
+
+	// CorruptExtrinsics, if true, flips a random byte in every extrinsic
+	// passed through MaybeCorrupt.
+	CorruptExtrinsics bool
+}
+
+// proxyPortOffset is added to a node's RPCPort to derive the local port its
+// DelayProxy listens on.
+const proxyPortOffset = 10000
+
+type delayProxyEntry struct {
+	addr  string
+	proxy *DelayProxy
+}
+
+// Injector attaches fault injection to a Network.
+type Injector struct {
+	net *simulation.Network
+	cfg Config
+
+	mu        sync.Mutex
+	partition *Partition
+	proxies   map[string]*delayProxyEntry
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an Injector for net using cfg.
+func New(net *simulation.Network, cfg Config) *Injector {
+	return &Injector{
+		net:  net,
+		cfg:  cfg,
+		stop: make(chan struct{}),
+	}
+}
+
+// Start begins injecting every fault enabled in the Injector's Config,
+// until Stop is called.
+func (inj *Injector) Start() {
+	if inj.cfg.KillEvery > 0 {
+		inj.wg.Add(1)
+		go inj.runEvery(inj.cfg.KillEvery, inj.killRandomNode)
+	}
+	if inj.cfg.PartitionEvery > 0 {
+		inj.wg.Add(1)
+		go inj.runEvery(inj.cfg.PartitionEvery, inj.repartition)
+	}
+	if inj.cfg.RPCDelay > 0 {
+		inj.startProxies()
+	}
+}
+
+// startProxies puts a DelayProxy in front of every node's RPC port.
+func (inj *Injector) startProxies() {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.proxies = make(map[string]*delayProxyEntry)
+	for _, node := range inj.net.Nodes() {
+		cfg := node.Config()
+		addr := fmt.Sprintf("localhost:%d", cfg.RPCPort+proxyPortOffset)
+
+		proxy := NewDelayProxy(addr, cfg.RPCAddr(), inj.cfg.RPCDelay)
+		if err := proxy.Start(); err != nil {
+			fmt.Printf("faults: failed to start delay proxy for node %s: %s\n", cfg.ID, err)
+			continue
+		}
+		inj.proxies[cfg.ID] = &delayProxyEntry{addr: "http://" + addr, proxy: proxy}
+	}
+}
+
+// RPCAddr returns the RPC endpoint callers should dial to reach node: its
+// real endpoint, or the DelayProxy put in front of it when Config.RPCDelay
+// is enabled.
+func (inj *Injector) RPCAddr(node simulation.Node) string {
+	inj.mu.Lock()
+	entry, ok := inj.proxies[node.Config().ID]
+	inj.mu.Unlock()
+
+	if !ok {
+		return node.Config().RPCAddr()
+	}
+	return entry.addr
+}
+
+// Stop halts all fault injection goroutines, heals any active partition,
+// and stops every DelayProxy.
+func (inj *Injector) Stop() {
+	close(inj.stop)
+	inj.wg.Wait()
+
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	if inj.partition != nil {
+		_ = inj.partition.Heal()
+		inj.partition = nil
+	}
+	for id, entry := range inj.proxies {
+		if err := entry.proxy.Stop(); err != nil {
+			fmt.Printf("faults: failed to stop delay proxy for node %s: %s\n", id, err)
+		}
+	}
+}
+
+func (inj *Injector) runEvery(interval time.Duration, fn func() error) {
+	defer inj.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-inj.stop:
+			return
+		case <-ticker.C:
+			if err := fn(); err != nil {
+				fmt.Printf("faults: %s\n", err)
+			}
+		}
+	}
+}
+
+// killRandomNode kills and immediately restarts one random node in the
+// network, simulating a crash/restart.
+func (inj *Injector) killRandomNode() error {
+	nodes := inj.net.Nodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	node := nodes[rand.Intn(len(nodes))]
+	id := node.Config().ID
+
+	if err := node.Stop(); err != nil {
+		return fmt.Errorf("failed to kill node %s: %w", id, err)
+	}
+	if err := node.Start(); err != nil {
+		return fmt.Errorf("failed to restart node %s: %w", id, err)
+	}
+
+	fmt.Printf("faults: killed and restarted node %s\n", id)
+	return nil
+}
+
+// repartition heals the previous partition (if any) and splits the network
+// into two new random halves.
+func (inj *Injector) repartition() error {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	if inj.partition != nil {
+		if err := inj.partition.Heal(); err != nil {
+			return err
+		}
+	}
+
+	nodes := inj.net.Nodes()
+	if len(nodes) < 2 {
+		return nil
+	}
+
+	shuffled := append([]simulation.Node{}, nodes...)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	mid := len(shuffled) / 2
+
+	p, err := Split(shuffled[:mid], shuffled[mid:])
+	if err != nil {
+		return err
+	}
+
+	inj.partition = p
+	fmt.Printf("faults: partitioned network into groups of %d and %d\n", mid, len(shuffled)-mid)
+	return nil
+}
+
+// MaybeCorrupt returns tx unchanged, or with a single random byte flipped
+// if the Injector's Config.CorruptExtrinsics is enabled.
+func (inj *Injector) MaybeCorrupt(tx []byte) []byte {
+	if !inj.cfg.CorruptExtrinsics {
+		return tx
+	}
+	return Corrupt(tx)
+}
+
+// Corrupt flips a single random byte of tx, simulating on-the-wire
+// corruption of a submitted extrinsic. It never mutates an empty slice.
+func Corrupt(tx []byte) []byte {
+	if len(tx) == 0 {
+		return tx
+	}
+
+	corrupted := append([]byte{}, tx...)
+	i := rand.Intn(len(corrupted))
+	corrupted[i] ^= 0xff
+	return corrupted
+}
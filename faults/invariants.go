@@ -0,0 +1,169 @@
+package faults
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+
+	"github.com/noot/gossamer-demo-extrinsic/alert"
+	"github.com/noot/gossamer-demo-extrinsic/simulation"
+)
+
+// InvariantConfig controls which invariants Invariants checks, and how
+// often.
+type InvariantConfig struct {
+	// CheckEvery is how often storage divergence and block production are
+	// checked.
+	CheckEvery time.Duration
+	// Key is the storage key checked for divergence across nodes.
+	Key []byte
+	// NoBlocksFor is how long the network's best block can stay unchanged
+	// before Invariants alerts that block production has stalled.
+	NoBlocksFor time.Duration
+}
+
+// Invariants watches a running Network for conditions that should never
+// happen — storage divergence across nodes, a node exiting unexpectedly,
+// or block production stalling — and fires an alert through alerter when
+// one does.
+type Invariants struct {
+	net     *simulation.Network
+	cfg     InvariantConfig
+	alerter *alert.Alerter
+
+	lastBlock         uint64
+	lastBlockSeen     time.Time
+	blockStallAlerted bool
+
+	stop chan struct{}
+}
+
+// NewInvariants creates an Invariants checker for net.
+func NewInvariants(net *simulation.Network, cfg InvariantConfig, alerter *alert.Alerter) *Invariants {
+	return &Invariants{
+		net:     net,
+		cfg:     cfg,
+		alerter: alerter,
+		stop:    make(chan struct{}),
+	}
+}
+
+// Start begins watching every node for unexpected exits, and periodically
+// checking storage divergence and block production, until Stop is called.
+func (inv *Invariants) Start() {
+	for _, node := range inv.net.Nodes() {
+		go inv.watchExit(node)
+	}
+
+	go func() {
+		ticker := time.NewTicker(inv.cfg.CheckEvery)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-inv.stop:
+				return
+			case <-ticker.C:
+				inv.checkStorageDivergence()
+				inv.checkBlockProduction()
+			}
+		}
+	}()
+}
+
+// Stop halts invariant checking.
+func (inv *Invariants) Stop() {
+	close(inv.stop)
+}
+
+// watchExit blocks on node.Wait() and alerts if the node exits with an
+// error once invariant checking is still active (a non-zero exit during
+// Stop's own teardown isn't itself an invariant violation).
+func (inv *Invariants) watchExit(node simulation.Node) {
+	err := node.Wait()
+
+	select {
+	case <-inv.stop:
+		return
+	default:
+	}
+
+	if err != nil {
+		inv.alerter.Alert(fmt.Sprintf("node %s exited with error: %s", node.Config().ID, err))
+	}
+}
+
+func (inv *Invariants) checkStorageDivergence() {
+	var (
+		first     string
+		firstNode string
+		haveFirst bool
+	)
+
+	for _, node := range inv.net.Nodes() {
+		respBody, err := simulation.PostRPC("state_getStorage", node.Config().RPCAddr(),
+			`["`+common.BytesToHex(inv.cfg.Key)+`"]`)
+		if err != nil {
+			continue
+		}
+
+		var v string
+		if err := simulation.DecodeRPC(respBody, &v); err != nil {
+			continue
+		}
+
+		if !haveFirst {
+			first, firstNode, haveFirst = v, node.Config().ID, true
+			continue
+		}
+
+		if v != first {
+			inv.alerter.Alert(fmt.Sprintf("storage divergence on key %x: node %s has %q, node %s has %q",
+				inv.cfg.Key, firstNode, first, node.Config().ID, v))
+		}
+	}
+}
+
+type chainHeader struct {
+	Number string `json:"number"`
+}
+
+func (inv *Invariants) checkBlockProduction() {
+	var maxBlock uint64
+
+	for _, node := range inv.net.Nodes() {
+		respBody, err := simulation.PostRPC("chain_getHeader", node.Config().RPCAddr(), "[]")
+		if err != nil {
+			continue
+		}
+
+		var header chainHeader
+		if err := simulation.DecodeRPC(respBody, &header); err != nil {
+			continue
+		}
+
+		n, err := strconv.ParseUint(strings.TrimPrefix(header.Number, "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		if n > maxBlock {
+			maxBlock = n
+		}
+	}
+
+	now := time.Now()
+	if maxBlock > inv.lastBlock || inv.lastBlockSeen.IsZero() {
+		inv.lastBlock = maxBlock
+		inv.lastBlockSeen = now
+		inv.blockStallAlerted = false
+		return
+	}
+
+	if !inv.blockStallAlerted && now.Sub(inv.lastBlockSeen) >= inv.cfg.NoBlocksFor {
+		inv.alerter.Alert(fmt.Sprintf("no new blocks for %s, stuck at block %d", inv.cfg.NoBlocksFor, inv.lastBlock))
+		inv.blockStallAlerted = true
+	}
+}
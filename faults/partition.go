@@ -0,0 +1,89 @@
+package faults
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/noot/gossamer-demo-extrinsic/simulation"
+)
+
+// Partition represents an active network split between two groups of
+// nodes, enforced by firewalling each node's libp2p port against the
+// other group's, the way a real network partition would look to libp2p.
+type Partition struct {
+	b []simulation.Node
+}
+
+// Split firewalls every node in group b against group a's traffic (and
+// implicitly vice versa, since both directions of a TCP stream are blocked
+// by dropping one side's port), via pf on macOS and iptables everywhere else.
+func Split(a, b []simulation.Node) (*Partition, error) {
+	p := &Partition{b: b}
+
+	for _, nodeB := range b {
+		if err := blockPort(nodeB.Config().P2PPort); err != nil {
+			_ = p.Heal()
+			return nil, fmt.Errorf("failed to block port %d: %w", nodeB.Config().P2PPort, err)
+		}
+	}
+
+	return p, nil
+}
+
+// Heal removes every firewall rule this Partition installed.
+func (p *Partition) Heal() error {
+	var firstErr error
+	for _, nodeB := range p.b {
+		if err := unblockPort(nodeB.Config().P2PPort); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func blockPort(port int) error {
+	if runtime.GOOS == "darwin" {
+		return pfBlock(port)
+	}
+	return iptablesBlock(port)
+}
+
+func unblockPort(port int) error {
+	if runtime.GOOS == "darwin" {
+		return pfUnblock(port)
+	}
+	return iptablesUnblock(port)
+}
+
+func iptablesBlock(port int) error {
+	return exec.Command("iptables", "-A", "INPUT", "-p", "tcp", "--dport", fmt.Sprint(port), "-j", "DROP").Run()
+}
+
+func iptablesUnblock(port int) error {
+	return exec.Command("iptables", "-D", "INPUT", "-p", "tcp", "--dport", fmt.Sprint(port), "-j", "DROP").Run()
+}
+
+// pfBlockedPorts holds every port blocked since the last flush, since
+// `pfctl -f -` replaces the loaded ruleset rather than adding to it.
+var pfBlockedPorts []int
+
+func pfBlock(port int) error {
+	pfBlockedPorts = append(pfBlockedPorts, port)
+	rules := &strings.Builder{}
+	for _, p := range pfBlockedPorts {
+		fmt.Fprintf(rules, "block in proto tcp to port %d\n", p)
+	}
+
+	cmd := exec.Command("pfctl", "-f", "-")
+	cmd.Stdin = strings.NewReader(rules.String())
+	return cmd.Run()
+}
+
+func pfUnblock(port int) error {
+	// Rules loaded via `pfctl -f -` live in the default anchor; flushing
+	// it drops every rule this package installed.
+	pfBlockedPorts = nil
+	return exec.Command("pfctl", "-F", "rules").Run()
+}
@@ -1,28 +1,25 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"math/rand"
-	"net"
-	"net/http"
 	"os"
-	"os/exec"
-	"strconv"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/ChainSafe/gossamer/lib/common"
-	"github.com/ChainSafe/gossamer/lib/common/optional"
-	"github.com/ChainSafe/gossamer/lib/runtime/extrinsic"
 	"github.com/urfave/cli"
+
+	"github.com/noot/gossamer-demo-extrinsic/alert"
+	"github.com/noot/gossamer-demo-extrinsic/faults"
+	"github.com/noot/gossamer-demo-extrinsic/simlog"
+	"github.com/noot/gossamer-demo-extrinsic/simulation"
+	"github.com/noot/gossamer-demo-extrinsic/txfactory"
 )
 
 var (
@@ -40,126 +37,95 @@ var (
 		Name:  "path",
 		Usage: "path to gossamer binary",
 	}
-)
-
-var flags = []cli.Flag{
-	numFlag,
-	connectFlag,
-	pathFlag,
-}
 
-var (
-	app          = cli.NewApp()
-	gossamerPath = "../../ChainSafe/gossamer/bin/gossamer"
-	baseaddr     = "/ip4/127.0.0.1/tcp/"
-
-	keys        = []string{"alice", "bob", "charlie", "dave", "eve", "fred", "george", "heather", "ian"}
-	baseRPCPort = 8540
-	baseport    = 7000
-	genesis     = "genesis.json"
-	config      = "config.toml"
-
-	maxRetries        = 36
-	httpClientTimeout = 120 * time.Second
-	dialTimeout       = 60 * time.Second
-
-	transport = &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout: dialTimeout,
-		}).Dial,
-	}
-	httpClient = &http.Client{
-		Transport: transport,
-		Timeout:   httpClientTimeout,
+	txKindFlag = cli.StringFlag{
+		Name:  "tx-kind",
+		Usage: "kind of extrinsic to submit: storage-change, raw-file",
+		Value: "storage-change",
 	}
-)
 
-// ServerResponse wraps the RPC response
-type ServerResponse struct {
-	// JSON-RPC Version
-	Version string `json:"jsonrpc"`
-	// Resulting values
-	Result json.RawMessage `json:"result"`
-	// Any generated errors
-	Error *Error `json:"error"`
-	// Request id
-	ID *json.RawMessage `json:"id"`
-}
-
-// ErrCode is a int type used for the rpc error codes
-type ErrCode int
+	fileFlag = cli.StringFlag{
+		Name:  "file",
+		Usage: "path to raw encoded extrinsic (raw-file)",
+	}
 
-// Error is a struct that holds the error message and the error code for a error
-type Error struct {
-	Message   string                 `json:"message"`
-	ErrorCode ErrCode                `json:"code"`
-	Data      map[string]interface{} `json:"data"`
-}
+	txCountFlag = cli.UintFlag{
+		Name:  "tx-count",
+		Usage: "submit N randomized extrinsics across random nodes concurrently instead of the single default one, printing per-node inclusion latency histograms",
+	}
 
-func postRPC(method, host, params string) ([]byte, error) {
-	data := []byte(`{"jsonrpc":"2.0","method":"` + method + `","params":` + params + `,"id":1}`)
-	buf := &bytes.Buffer{}
-	_, err := buf.Write(data)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	logLevelFlag = cli.StringFlag{
+		Name:  "log-level",
+		Usage: "log level: crit, error, warn, info, debug",
+		Value: "info",
 	}
 
-	r, err := http.NewRequest("POST", host, buf)
-	if err != nil {
-		return nil, err
+	chaosKillEveryFlag = cli.DurationFlag{
+		Name:  "chaos-kill-every",
+		Usage: "kill and restart a random node on this interval (0 disables)",
 	}
 
-	r.Header.Set("Content-Type", "application/json")
-	r.Header.Set("Accept", "application/json")
+	chaosPartitionEveryFlag = cli.DurationFlag{
+		Name:  "chaos-partition-every",
+		Usage: "split the network into two random halves on this interval (0 disables)",
+	}
 
-	resp, err := httpClient.Do(r)
-	if err != nil {
-		return nil, err
-	} else if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status code not OK")
+	alertWebhookFlag = cli.StringFlag{
+		Name:  "alert-webhook",
+		Usage: "webhook URL to additionally deliver invariant-violation alerts to",
 	}
 
-	defer func() {
-		_ = resp.Body.Close()
-	}()
+	chaosCorruptFlag = cli.BoolFlag{
+		Name:  "chaos-corrupt-extrinsics",
+		Usage: "flip a random byte of every submitted extrinsic before sending it",
+	}
 
-	respBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	chaosRPCDelayFlag = cli.DurationFlag{
+		Name:  "chaos-rpc-delay",
+		Usage: "delay every RPC response by this much via a local proxy in front of each node (0 disables)",
 	}
+)
 
-	return respBody, nil
+var flags = []cli.Flag{
+	numFlag,
+	connectFlag,
+	pathFlag,
+	txKindFlag,
+	fileFlag,
+	txCountFlag,
+	logLevelFlag,
+	chaosKillEveryFlag,
+	chaosPartitionEveryFlag,
+	alertWebhookFlag,
+	chaosCorruptFlag,
+	chaosRPCDelayFlag,
 }
 
-func decodeRPC(body []byte, target interface{}) error {
-	decoder := json.NewDecoder(bytes.NewReader(body))
-	decoder.DisallowUnknownFields()
+var (
+	app          = cli.NewApp()
+	gossamerPath = "../../ChainSafe/gossamer/bin/gossamer"
 
-	var response ServerResponse
-	err := decoder.Decode(&response)
-	if err != nil {
-		return err
-	}
+	keys        = []string{"alice", "bob", "charlie", "dave", "eve", "fred", "george", "heather", "ian"}
+	baseRPCPort = 8540
+	baseport    = 7000
+	genesis     = "genesis.json"
+	config      = "config.toml"
 
-	if response.Error != nil {
-		return errors.New(response.Error.Message)
-	}
+	maxRetries = 36
+)
 
-	decoder = json.NewDecoder(bytes.NewReader(response.Result))
-	decoder.DisallowUnknownFields()
-	return decoder.Decode(target)
-}
+// logger carries harness-level orchestration events (as opposed to
+// per-node events, which go through each node's own Logger).
+var logger = simlog.NewRoot()
 
 func getStorage(endpoint string, key []byte) ([]byte, error) {
-	respBody, err := postRPC("state_getStorage", endpoint, "[\""+common.BytesToHex(key)+"\"]")
+	respBody, err := simulation.PostRPC("state_getStorage", endpoint, "[\""+common.BytesToHex(key)+"\"]")
 	if err != nil {
 		return nil, err
 	}
 
 	v := new(string)
-	err = decodeRPC(respBody, v)
+	err = simulation.DecodeRPC(respBody, v)
 	if err != nil {
 		return nil, err
 	}
@@ -168,100 +134,77 @@ func getStorage(endpoint string, key []byte) ([]byte, error) {
 		return []byte{}, nil
 	}
 
-	value, err := common.HexToBytes(*v)
-	if err != nil {
-		return nil, err
-	}
-
-	return value, nil
+	return common.HexToBytes(*v)
 }
 
-func initAndStart(idx int, genesis, bootnodes string, outfile, errfile *os.File) *exec.Cmd {
-	basepath := "~/.gossamer_" + keys[idx]
-
-	initCmd := exec.Command(gossamerPath,
-		"init",
-		"--config", config,
-		"--basepath", basepath,
-		"--genesis", genesis,
-		"--force",
-	)
+func init() {
+	app.Action = run
+	app.Flags = flags
+}
 
-	// init gossamer
-	stdout, err := initCmd.CombinedOutput()
-	if err != nil {
-		fmt.Printf("failed to initialize node %d: %s\n", idx, err)
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+}
 
-	outfile.Write(stdout)
-	fmt.Println("initialized node", keys[idx])
-
-	gssmrCmd := exec.Command(gossamerPath,
-		"--port", strconv.Itoa(baseport+idx),
-		"--config", config,
-		"--key", keys[idx],
-		"--basepath", basepath,
-		"--rpcport", strconv.Itoa(baseRPCPort+idx),
-		"--rpc",
-		"--bootnodes", bootnodes,
-	)
-
-	stdoutPipe, err := gssmrCmd.StdoutPipe()
-	if err != nil {
-		fmt.Printf("failed to get stdoutPipe from node %d: %s\n", idx, err)
-		os.Exit(1)
-	}
-
-	stderrPipe, err := gssmrCmd.StderrPipe()
-	if err != nil {
-		fmt.Printf("failed to get stderrPipe from node %d: %s\n", idx, err)
-		os.Exit(1)
+// buildAlerter wires up the sinks invariant violations are reported to:
+// stderr always, plus a webhook if --alert-webhook was given.
+func buildAlerter(ctx *cli.Context) *alert.Alerter {
+	sinks := []alert.Sink{alert.StderrSink{}}
+	if url := ctx.String(alertWebhookFlag.Name); url != "" {
+		sinks = append(sinks, alert.WebhookSink{URL: url})
 	}
+	return alert.New(sinks...)
+}
 
-	err = gssmrCmd.Start()
-	if err != nil {
-		fmt.Printf("failed to start node %d: %s\n", idx, err)
-		os.Exit(1)
-	}
+// buildNetwork creates and starts num nodes using the exec adapter, directly
+// connecting them in a star topology through node 0 if connect is set, and
+// relying on mDNS discovery otherwise.
+func buildNetwork(num int, connect bool) (*simulation.Network, error) {
+	net := simulation.NewNetwork(simulation.NewExecAdapter())
 
-	writer := bufio.NewWriter(outfile)
-	go io.Copy(writer, stdoutPipe)
-	errWriter := bufio.NewWriter(errfile)
-	go io.Copy(errWriter, stderrPipe)
-	return gssmrCmd
-}
+	for i := 0; i < num; i++ {
+		cfg := &simulation.NodeConfig{
+			ID:           keys[i],
+			Index:        i,
+			GossamerPath: gossamerPath,
+			BasePath:     "~/.gossamer_" + keys[i],
+			Genesis:      genesis,
+			ConfigFile:   config,
+			P2PPort:      baseport + i,
+			RPCPort:      baseRPCPort + i,
+			Logger:       simlog.New(keys[i], baseRPCPort+i),
+		}
 
-func getPeerID(endpoint string) (string, error) {
-	respBody, err := postRPC("system_networkState", endpoint, "[]")
-	if err != nil {
-		return "", err
+		if _, err := net.NewNode(cfg); err != nil {
+			return nil, err
+		}
 	}
 
-	networkState := new(modules.SystemNetworkStateResponse)
-	err = decodeRPC(respBody, &networkState)
-	if err != nil {
-		return "", err
+	if err := net.StartAll(); err != nil {
+		return nil, err
 	}
 
-	return networkState.NetworkState.PeerID, nil
-}
-
-func init() {
-	app.Action = run
-	app.Flags = flags
-}
+	// wait for nodes to start
+	time.Sleep(time.Second * 5)
 
-func main() {
-	if err := app.Run(os.Args); err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	if connect {
+		// all other nodes connect directly to node 0; the rest discover
+		// each other through that connection as well as mDNS.
+		for i := 1; i < num; i++ {
+			if err := net.Connect(keys[i], keys[0]); err != nil {
+				return nil, fmt.Errorf("failed to connect %s to %s: %w", keys[i], keys[0], err)
+			}
+			logger.Info("connected nodes", "from", keys[i], "to", keys[0])
+		}
 	}
+
+	return net, nil
 }
 
 func run(ctx *cli.Context) error {
-	var err error
-
 	num := int(ctx.Uint(numFlag.Name))
 	if num%3 != 0 {
 		fmt.Print("must do 3, 6, 9 nodes")
@@ -274,102 +217,74 @@ func run(ctx *cli.Context) error {
 		gossamerPath = path
 	}
 
-	fmt.Println("num nodes:", num)
-
-	// initialize and start nodes
-	processes := []*exec.Cmd{}
-
-	var wg sync.WaitGroup
-	wg.Add(num)
-	var nodeAddr string // used for directly connecting nodes
-
-	for i := 0; i < num; i++ {
-		outfile, err := os.Create("./log_" + keys[i] + ".out")
-		if err != nil {
-			panic(err)
-		}
-		defer outfile.Close()
-
-		errfile, err := os.Create("./err_" + keys[i] + ".out")
-		if err != nil {
-			panic(err)
-		}
-		defer errfile.Close()
-
-		if connect && i == 0 {
-			// all other nodes will directly connect to the first node
-			// the other nodes are able to discover each other through the connection to the first node
-			// as well as mDNS
-			p := initAndStart(i, genesis, "", outfile, errfile)
-			processes = append(processes, p)
-			wg.Done()
-
-			var peerID string
-			for j := 0; j < maxRetries; j++ {
-				peerID, err = getPeerID("http://localhost:" + strconv.Itoa(baseRPCPort))
-				if err == nil {
-					break
-				}
-				time.Sleep(time.Second)
-			}
-
-			if err != nil {
-				fmt.Println("failed to get peerID from first node")
-				return err
-			}
-
-			nodeAddr = baseaddr + strconv.Itoa(baseport) + "/p2p/" + peerID
-			fmt.Println("got node addr for node", nodeAddr)
-			continue
-		}
-
-		go func(i int, outfile *os.File) {
-			p := initAndStart(i, genesis, nodeAddr, outfile, errfile)
-			processes = append(processes, p)
-			wg.Done()
-		}(i, outfile)
+	if err := simlog.SetLevel(ctx.String(logLevelFlag.Name)); err != nil {
+		return err
 	}
-	wg.Wait()
+	logger = simlog.NewRoot()
 
-	for i := 0; i < num; i++ {
-		go func(i int) {
-			err = processes[i].Wait()
-			if err != nil {
-				fmt.Printf("process %s failed!!! %s\n", keys[i], err)
-			}
-		}(i)
-	}
+	logger.Info("starting network", "numNodes", num)
 
+	net, err := buildNetwork(num, connect)
+	if err != nil {
+		return err
+	}
 	defer func() {
-		for i := 0; i < num; i++ {
-			err = processes[i].Process.Kill()
-			if err != nil {
-				fmt.Printf("could not kill process %s!!! %s\n", keys[i], err)
-			}
+		if err := net.Shutdown(); err != nil {
+			logger.Error("error during shutdown", "err", err)
 		}
 	}()
 
-	// wait for nodes to start
-	time.Sleep(time.Second * 5)
+	alerter := buildAlerter(ctx)
+	invariants := faults.NewInvariants(net, faults.InvariantConfig{
+		CheckEvery:  5 * time.Second,
+		Key:         []byte("noot"),
+		NoBlocksFor: 30 * time.Second,
+	}, alerter)
+	invariants.Start()
+	defer invariants.Stop()
+
+	injector := faults.New(net, faults.Config{
+		KillEvery:         ctx.Duration(chaosKillEveryFlag.Name),
+		PartitionEvery:    ctx.Duration(chaosPartitionEveryFlag.Name),
+		CorruptExtrinsics: ctx.Bool(chaosCorruptFlag.Name),
+		RPCDelay:          ctx.Duration(chaosRPCDelayFlag.Name),
+	})
+	injector.Start()
+	defer injector.Stop()
+
+	factory, err := txfactory.Get(ctx.String(txKindFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	if count := int(ctx.Uint(txCountFlag.Name)); count > 0 {
+		return submitLoad(net, num, factory, injector, count)
+	}
 
-	// create StorageChange extrinsic
 	key := []byte("noot")
 	value := []byte("washere")
-	ext := extrinsic.NewStorageChangeExt(key, optional.NewBytes(true, value))
-	tx, err := ext.Encode()
+
+	tx, err := factory.Build(txfactory.Params{
+		Key:   key,
+		Value: value,
+		File:  ctx.String(fileFlag.Name),
+	})
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	tx = injector.MaybeCorrupt(tx)
 
 	txStr := hex.EncodeToString(tx)
 
 	// get storage before
 	fmt.Println("storage before")
 	for i := 0; i < num; i++ {
+		node, _ := net.Get(keys[i])
+
 		var res []byte
 		for j := 0; j < 8; j++ {
-			res, err = getStorage("http://localhost:"+strconv.Itoa(baseRPCPort+i), key)
+			res, err = getStorage(injector.RPCAddr(node), key)
 			if err == nil {
 				break
 			}
@@ -380,37 +295,206 @@ func run(ctx *cli.Context) error {
 		fmt.Printf("got storage from node %d: 0x%x\n", i, res)
 	}
 
-	// submit extrinsic
+	// submit extrinsic and watch it through to finalization instead of
+	// polling state_getStorage, using author_submitAndWatchExtrinsic.
 	r := rand.Intn(num)
-	respBody, err := postRPC("author_submitExtrinsic", "http://localhost:"+strconv.Itoa(baseRPCPort+r), "\"0x"+txStr+"\"")
-	if err != nil {
+	target, _ := net.Get(keys[r])
+	logger.Info("submitting extrinsic", "node", keys[r])
+
+	if err := watchExtrinsic(injector.RPCAddr(target), txStr, "finalized"); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("submitted extrinsic to node %d\n", r)
-	fmt.Printf("response: %s\n", respBody)
+	// query for storage, subscribing to changes on the "noot" key so we're
+	// notified as soon as each node applies the block, alongside a
+	// once-a-second state_getStorage poll.
+	type result struct {
+		idx int
+		res []byte
+	}
+	results := make(chan result, num)
 
-	// query for storage
 	for i := 0; i < num; i++ {
-		wg.Add(1)
-
 		go func(i int) {
-			var res []byte
-			for j := 0; j < maxRetries; j++ {
-				res, err = getStorage("http://localhost:"+strconv.Itoa(baseRPCPort+i), key)
-				if err == nil && !bytes.Equal(res, []byte{}) {
-					break
+			node, _ := net.Get(keys[i])
+			results <- result{idx: i, res: watchStorage(injector.RPCAddr(node), key)}
+		}(i)
+	}
+
+	for i := 0; i < num; i++ {
+		res := <-results
+		fmt.Printf("got storage from node %d: 0x%x\n", res.idx, res.res)
+	}
+
+	return nil
+}
+
+// watchExtrinsic submits txHex via author_submitAndWatchExtrinsic and blocks
+// until its status reaches until, e.g. "inBlock" or "finalized" (or the
+// node reports an error).
+func watchExtrinsic(endpoint, txHex, until string) error {
+	done := make(chan error, 1)
+
+	client, err := simulation.WSSubscribe(endpoint, "author_submitAndWatchExtrinsic",
+		`["0x`+txHex+`"]`, func(notification json.RawMessage) {
+			var status map[string]interface{}
+			if err := json.Unmarshal(notification, &status); err != nil {
+				// status may be a bare string, e.g. "ready" or "broadcast"
+				var s string
+				if err := json.Unmarshal(notification, &s); err == nil {
+					logger.Info("extrinsic status", "status", s)
+				}
+				return
+			}
+
+			if _, ok := status[until]; ok {
+				logger.Info("extrinsic status", "status", until, "hash", status[until])
+				done <- nil
+			}
+		})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Duration(maxRetries) * time.Second):
+		return fmt.Errorf("timed out waiting for extrinsic status %s", until)
+	}
+}
+
+// watchStorage subscribes to state_subscribeStorage for key on endpoint and
+// returns the first non-empty value observed either through that
+// subscription or through a state_getStorage poll made once a second,
+// returning nil after maxRetries polls with no value.
+func watchStorage(endpoint string, key []byte) []byte {
+	resultc := make(chan []byte, 1)
+
+	client, err := simulation.WSSubscribe(endpoint, "state_subscribeStorage",
+		`[["`+common.BytesToHex(key)+`"]]`, func(notification json.RawMessage) {
+			var changes struct {
+				Changes [][2]string `json:"changes"`
+			}
+			if err := json.Unmarshal(notification, &changes); err != nil {
+				return
+			}
+			for _, change := range changes.Changes {
+				value, err := common.HexToBytes(change[1])
+				if err == nil && len(value) > 0 {
+					select {
+					case resultc <- value:
+					default:
+					}
 				}
+			}
+		})
+	if err == nil {
+		defer client.Close()
+	}
+
+	for j := 0; j < maxRetries; j++ {
+		select {
+		case res := <-resultc:
+			return res
+		default:
+		}
+
+		res, err := getStorage(endpoint, key)
+		if err == nil && !bytes.Equal(res, []byte{}) {
+			return res
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	return nil
+}
 
-				time.Sleep(time.Second)
+// submitLoad submits count randomized extrinsics built by factory across
+// random nodes concurrently, then prints a per-node inclusion latency
+// histogram. It's used by --tx-count for throughput measurement instead of
+// the single default extrinsic submitted by run.
+func submitLoad(net *simulation.Network, num int, factory txfactory.Factory, injector *faults.Injector, count int) error {
+	type sample struct {
+		node    int
+		latency time.Duration
+		err     error
+	}
+
+	samples := make(chan sample, count)
+	var wg sync.WaitGroup
+	wg.Add(count)
+
+	for i := 0; i < count; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			r := rand.Intn(num)
+			node, _ := net.Get(keys[r])
+
+			tx, err := factory.Build(txfactory.Params{
+				Key:   []byte("noot"),
+				Value: []byte(fmt.Sprintf("load-%d-%d", i, rand.Int())),
+			})
+			if err != nil {
+				samples <- sample{node: r, err: err}
+				return
 			}
+			tx = injector.MaybeCorrupt(tx)
 
-			fmt.Printf("got storage from node %d: 0x%x\n", i, res)
-			wg.Done()
+			start := time.Now()
+			err = watchExtrinsic(injector.RPCAddr(node), hex.EncodeToString(tx), "inBlock")
+			samples <- sample{node: r, latency: time.Since(start), err: err}
 		}(i)
 	}
 
 	wg.Wait()
+	close(samples)
+
+	latenciesByNode := make(map[int][]time.Duration)
+	var failed int
+	for s := range samples {
+		if s.err != nil {
+			failed++
+			continue
+		}
+		latenciesByNode[s.node] = append(latenciesByNode[s.node], s.latency)
+	}
+
+	fmt.Printf("submitted %d extrinsics, %d failed\n", count, failed)
+	for i := 0; i < num; i++ {
+		printLatencyHistogram(i, latenciesByNode[i])
+	}
+
 	return nil
 }
+
+// printLatencyHistogram prints a simple text histogram of per-second
+// inclusion latency buckets for a single node.
+func printLatencyHistogram(node int, latencies []time.Duration) {
+	if len(latencies) == 0 {
+		fmt.Printf("node %d: no extrinsics included\n", node)
+		return
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	fmt.Printf("node %d: %d extrinsics, min=%s max=%s\n", node, len(latencies), latencies[0], latencies[len(latencies)-1])
+
+	buckets := make(map[time.Duration]int)
+	for _, l := range latencies {
+		buckets[l.Round(time.Second)]++
+	}
+
+	bucketKeys := make([]time.Duration, 0, len(buckets))
+	for b := range buckets {
+		bucketKeys = append(bucketKeys, b)
+	}
+	sort.Slice(bucketKeys, func(i, j int) bool { return bucketKeys[i] < bucketKeys[j] })
+
+	for _, b := range bucketKeys {
+		fmt.Printf("  %6s | %s (%d)\n", b, strings.Repeat("#", buckets[b]), buckets[b])
+	}
+}
@@ -0,0 +1,46 @@
+package simlog
+
+import (
+	"bytes"
+	"sync"
+
+	log "github.com/ChainSafe/log15"
+)
+
+// Capture mirrors everything written to a Logger into an in-memory buffer,
+// so downstream tests can assert on log output programmatically instead of
+// scraping stdout or a log file.
+type Capture struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Attach wires c into logger's handler chain alongside its existing
+// handler, returning c for convenience.
+func Attach(logger Logger, c *Capture) *Capture {
+	existing := logger.GetHandler()
+	logger.SetHandler(log.MultiHandler(
+		existing,
+		log.StreamHandler(c, fractionalFormat()),
+	))
+	return c
+}
+
+// NewCapture creates a Capture already attached to logger.
+func NewCapture(logger Logger) *Capture {
+	return Attach(logger, &Capture{})
+}
+
+// Write implements io.Writer.
+func (c *Capture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.Write(p)
+}
+
+// Output returns everything captured so far.
+func (c *Capture) Output() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]byte(nil), c.buf.Bytes()...)
+}
@@ -0,0 +1,128 @@
+// Package simlog provides the structured, leveled logging used across the
+// harness, replacing the old fmt.Println/os.Create("./log_alice.out")
+// scheme. Every per-node logger tags its lines with the node's key and RPC
+// port (and its PID, once WithPID is applied), and every logger timestamps
+// its lines to fractional-second precision.
+package simlog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/ChainSafe/log15"
+)
+
+// Logger is the logger type used throughout the harness.
+type Logger = log.Logger
+
+// fractionalTimeFormat gives log lines sub-second precision, which matters
+// when comparing event ordering across several nodes running in lockstep.
+const fractionalTimeFormat = "2006-01-02T15:04:05.000000-0700"
+
+var currentLevel = log.LvlInfo
+
+// SetLevel sets the log level new loggers are created with. It accepts the
+// same strings as log15.LvlFromString (crit, error, warn, info, debug).
+func SetLevel(lvlString string) error {
+	lvl, err := log.LvlFromString(lvlString)
+	if err != nil {
+		return err
+	}
+	currentLevel = lvl
+	return nil
+}
+
+// New creates a Logger tagged with the given node key and RPC port, logging
+// to stdout at the current level. Callers add "pid" once the node's
+// process has actually started, via WithPID.
+func New(nodeKey string, rpcPort int) Logger {
+	logger := log.New("node", nodeKey, "rpcport", rpcPort)
+	logger.SetHandler(log.LvlFilterHandler(currentLevel, log.StreamHandler(os.Stdout, fractionalFormat())))
+	return logger
+}
+
+// NewRoot creates a Logger for harness-level orchestration events that
+// aren't tied to any single node.
+func NewRoot() Logger {
+	logger := log.New()
+	logger.SetHandler(log.LvlFilterHandler(currentLevel, log.StreamHandler(os.Stdout, fractionalFormat())))
+	return logger
+}
+
+// WithPID returns a child logger with a "pid" context field set, for use
+// once a node's process has been started.
+func WithPID(logger Logger, pid int) Logger {
+	return logger.New("pid", pid)
+}
+
+// fractionalFormat is log15's logfmt format with a fractional-second
+// timestamp in place of the default whole-second one, so that events from
+// several nodes running in lockstep can still be ordered relative to
+// each other.
+func fractionalFormat() log.Format {
+	return log.FormatFunc(func(r *log.Record) []byte {
+		buf := &bytes.Buffer{}
+		fmt.Fprintf(buf, "%s lvl=%s msg=%q", r.Time.Format(fractionalTimeFormat), r.Lvl, r.Msg)
+
+		for i := 0; i < len(r.Ctx)-1; i += 2 {
+			fmt.Fprintf(buf, " %v=%v", r.Ctx[i], r.Ctx[i+1])
+		}
+		buf.WriteByte('\n')
+
+		return buf.Bytes()
+	})
+}
+
+// LineWriter returns an io.Writer that forwards each newline-delimited line
+// written to it to logger at the given level. It's used to stream a child
+// process's stdout/stderr through the same structured handler as the
+// parent's own log lines, instead of piping raw bytes into a file.
+//
+// Unlike the old bufio.Writer-wrapped-outfile approach, it never buffers
+// partial output across a shutdown: each full line is flushed to the
+// logger as soon as it's seen, and Close flushes whatever partial line
+// remains.
+func LineWriter(logger Logger, lvl log.Lvl) io.WriteCloser {
+	pr, pw := io.Pipe()
+	lw := &lineWriter{pw: pw}
+
+	go func() {
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			logLine(logger, lvl, scanner.Text())
+		}
+	}()
+
+	return lw
+}
+
+type lineWriter struct {
+	pw *io.PipeWriter
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	return w.pw.Write(p)
+}
+
+func (w *lineWriter) Close() error {
+	return w.pw.Close()
+}
+
+func logLine(logger Logger, lvl log.Lvl, line string) {
+	switch lvl {
+	case log.LvlCrit:
+		logger.Crit(line)
+	case log.LvlError:
+		logger.Error(line)
+	case log.LvlWarn:
+		logger.Warn(line)
+	case log.LvlDebug:
+		logger.Debug(line)
+	default:
+		logger.Info(line)
+	}
+}
@@ -0,0 +1,81 @@
+// Package simulation provides a reusable harness for standing up and
+// wiring together multiple gossamer nodes, modeled after go-ethereum's
+// p2p/simulations package. It replaces the hard-coded 3/6/9 star topology
+// that used to live in main.go with a NodeAdapter abstraction so that
+// callers can choose how nodes are actually executed (separate processes,
+// in-process goroutines, or containers) while reusing the same topology
+// and lifecycle code.
+package simulation
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/noot/gossamer-demo-extrinsic/simlog"
+)
+
+// NodeConfig describes a single node to be created. It is adapter-agnostic:
+// every adapter is expected to honor these fields in whatever way makes
+// sense for its execution environment.
+type NodeConfig struct {
+	// ID is the node's human-readable key, e.g. "alice".
+	ID string
+	// Index is the node's position in the topology (used to derive ports).
+	Index int
+
+	GossamerPath string
+	BasePath     string
+	Genesis      string
+	ConfigFile   string
+
+	P2PPort int
+	RPCPort int
+
+	// Bootnodes is passed through to the node as its --bootnodes value.
+	// Empty means the node has no bootnodes configured.
+	Bootnodes string
+
+	// Stdout/Stderr receive the node's raw output if Logger is nil.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Logger, if set, takes precedence over Stdout/Stderr: ExecAdapter
+	// streams the node's output through it line-by-line instead, tagged
+	// with the node's key, PID, and RPC port. DockerAdapter does not use
+	// Logger and always writes to Stdout/Stderr; InProcAdapter uses
+	// neither.
+	Logger simlog.Logger
+}
+
+// RPCAddr returns the node's JSON-RPC HTTP endpoint, as configured by cfg.
+func (cfg *NodeConfig) RPCAddr() string {
+	return "http://localhost:" + strconv.Itoa(cfg.RPCPort)
+}
+
+// Node is a single running (or runnable) node within a Network, regardless
+// of which NodeAdapter created it.
+type Node interface {
+	// Start launches the node. It must not block past the point where the
+	// node's RPC server is listening.
+	Start() error
+	// Stop tears the node down, killing it if necessary.
+	Stop() error
+	// Wait blocks until the node exits and returns its exit error, if any.
+	Wait() error
+
+	// Config returns the NodeConfig the node was created with.
+	Config() *NodeConfig
+	// PeerID queries the node's own libp2p peer ID over RPC.
+	PeerID() (string, error)
+	// Multiaddr returns the node's dialable multiaddr, once known.
+	Multiaddr() (string, error)
+}
+
+// NodeAdapter knows how to create Nodes within a particular execution
+// environment (a subprocess, an in-memory goroutine, a container, ...).
+type NodeAdapter interface {
+	// Name identifies the adapter, e.g. "exec", "inproc", "docker".
+	Name() string
+	// NewNode creates (but does not start) a Node for cfg.
+	NewNode(cfg *NodeConfig) (Node, error)
+}
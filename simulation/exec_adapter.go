@@ -0,0 +1,214 @@
+package simulation
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/ChainSafe/log15"
+
+	"github.com/noot/gossamer-demo-extrinsic/simlog"
+)
+
+// execPeerRetries is the number of times an execNode (or dockerNode) will poll
+// system_networkState while waiting for its RPC server to come up.
+const execPeerRetries = 36
+
+// restartGraceTimeout bounds how long Wait will wait for Start to install a
+// replacement process after Stop kills the current one, before giving up
+// and treating the exit as a genuine crash.
+const restartGraceTimeout = 10 * time.Second
+
+// ExecAdapter runs each node as its own OS process via os/exec, the way the
+// original demo's initAndStart did. It's the default adapter for real
+// multi-process runs against a gossamer binary on disk.
+type ExecAdapter struct{}
+
+// NewExecAdapter creates an ExecAdapter.
+func NewExecAdapter() *ExecAdapter {
+	return &ExecAdapter{}
+}
+
+// Name implements NodeAdapter.
+func (a *ExecAdapter) Name() string {
+	return "exec"
+}
+
+// NewNode implements NodeAdapter.
+func (a *ExecAdapter) NewNode(cfg *NodeConfig) (Node, error) {
+	if cfg.GossamerPath == "" {
+		return nil, fmt.Errorf("exec adapter: GossamerPath must be set")
+	}
+	return &execNode{cfg: cfg}, nil
+}
+
+type execNode struct {
+	cfg *NodeConfig
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	stopped bool // true once Stop has killed the current cmd
+
+	stdoutWriter io.WriteCloser
+	stderrWriter io.WriteCloser
+}
+
+func (n *execNode) Config() *NodeConfig {
+	return n.cfg
+}
+
+func (n *execNode) Start() error {
+	cfg := n.cfg
+
+	initCmd := exec.Command(cfg.GossamerPath,
+		"init",
+		"--config", cfg.ConfigFile,
+		"--basepath", cfg.BasePath,
+		"--genesis", cfg.Genesis,
+		"--force",
+	)
+
+	stdout, err := initCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to initialize node %s: %w", cfg.ID, err)
+	}
+
+	switch {
+	case cfg.Logger != nil:
+		cfg.Logger.Info("initialized node", "output", string(stdout))
+	case cfg.Stdout != nil:
+		_, _ = cfg.Stdout.Write(stdout)
+	}
+
+	gssmrCmd := exec.Command(cfg.GossamerPath,
+		"--port", strconv.Itoa(cfg.P2PPort),
+		"--config", cfg.ConfigFile,
+		"--key", cfg.ID,
+		"--basepath", cfg.BasePath,
+		"--rpcport", strconv.Itoa(cfg.RPCPort),
+		"--rpc",
+		"--bootnodes", cfg.Bootnodes,
+	)
+
+	stdoutPipe, err := gssmrCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdoutPipe from node %s: %w", cfg.ID, err)
+	}
+
+	stderrPipe, err := gssmrCmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stderrPipe from node %s: %w", cfg.ID, err)
+	}
+
+	if err := gssmrCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start node %s: %w", cfg.ID, err)
+	}
+
+	var stdoutDst, stderrDst io.Writer
+	if cfg.Logger != nil {
+		logger := simlog.WithPID(cfg.Logger, gssmrCmd.Process.Pid)
+		n.stdoutWriter = simlog.LineWriter(logger, log.LvlInfo)
+		n.stderrWriter = simlog.LineWriter(logger, log.LvlError)
+		stdoutDst, stderrDst = n.stdoutWriter, n.stderrWriter
+	} else {
+		stdoutDst, stderrDst = cfg.Stdout, cfg.Stderr
+	}
+
+	if stdoutDst != nil {
+		go func() { _, _ = io.Copy(stdoutDst, stdoutPipe) }()
+	}
+	if stderrDst != nil {
+		go func() { _, _ = io.Copy(stderrDst, stderrPipe) }()
+	}
+
+	n.mu.Lock()
+	n.cmd = gssmrCmd
+	n.stopped = false
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *execNode) Stop() error {
+	n.mu.Lock()
+	cmd := n.cmd
+	if cmd == nil || cmd.Process == nil {
+		n.mu.Unlock()
+		return nil
+	}
+	n.stopped = true
+	n.mu.Unlock()
+	return cmd.Process.Kill()
+}
+
+// Wait blocks until the node's process exits. If that exit was caused by
+// our own Stop (e.g. faults.Injector's kill/restart cycle) rather than a
+// crash, Wait gives Start a grace period to install the replacement
+// process and waits on that instead, so an intentional restart never
+// surfaces as an invariant violation the way an unflagged exit would.
+func (n *execNode) Wait() error {
+	n.mu.Lock()
+	cmd := n.cmd
+	n.mu.Unlock()
+	if cmd == nil {
+		return fmt.Errorf("node %s was never started", n.cfg.ID)
+	}
+
+	err := cmd.Wait()
+
+	n.mu.Lock()
+	stopped := n.stopped
+	n.mu.Unlock()
+
+	// Closing these flushes any trailing partial line that io.Copy's last
+	// read might not have delivered a newline for, instead of silently
+	// dropping it the way an un-flushed bufio.Writer used to.
+	if n.stdoutWriter != nil {
+		_ = n.stdoutWriter.Close()
+	}
+	if n.stderrWriter != nil {
+		_ = n.stderrWriter.Close()
+	}
+
+	if !stopped {
+		return err
+	}
+
+	deadline := time.Now().Add(restartGraceTimeout)
+	for time.Now().Before(deadline) {
+		n.mu.Lock()
+		restarted := n.cmd != cmd
+		n.mu.Unlock()
+		if restarted {
+			return n.Wait()
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return err
+}
+
+func (n *execNode) PeerID() (string, error) {
+	var (
+		peerID string
+		err    error
+	)
+	for i := 0; i < execPeerRetries; i++ {
+		peerID, err = queryPeerID(n.cfg.RPCAddr())
+		if err == nil {
+			return peerID, nil
+		}
+		time.Sleep(time.Second)
+	}
+	return "", err
+}
+
+func (n *execNode) Multiaddr() (string, error) {
+	peerID, err := n.PeerID()
+	if err != nil {
+		return "", err
+	}
+	return "/ip4/127.0.0.1/tcp/" + strconv.Itoa(n.cfg.P2PPort) + "/p2p/" + peerID, nil
+}
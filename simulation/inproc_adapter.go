@@ -0,0 +1,118 @@
+package simulation
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot"
+)
+
+// InProcAdapter links gossamer in as a library and runs each node as a
+// goroutine within the harness's own process instead of a separate OS
+// process.
+//
+// The original ask for this adapter was an in-memory pipe transport in
+// place of real TCP sockets, for a further speedup on top of skipping
+// os/exec. That's descoped here: swapping gossamer's libp2p transport
+// requires reaching into dot/network internals this tree doesn't expose a
+// seam for, so nodes still bind a real TCP listener on P2PPort. The
+// adapter's actual win over ExecAdapter is narrower than originally
+// asked for — it only skips the fork/exec overhead — which still matters
+// for topology tests that spin up many short-lived networks.
+type InProcAdapter struct {
+	mu    sync.Mutex
+	nodes map[string]*inProcNode
+}
+
+// NewInProcAdapter creates an InProcAdapter.
+func NewInProcAdapter() *InProcAdapter {
+	return &InProcAdapter{
+		nodes: make(map[string]*inProcNode),
+	}
+}
+
+// Name implements NodeAdapter.
+func (a *InProcAdapter) Name() string {
+	return "inproc"
+}
+
+// NewNode implements NodeAdapter.
+func (a *InProcAdapter) NewNode(cfg *NodeConfig) (Node, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, exists := a.nodes[cfg.ID]; exists {
+		return nil, fmt.Errorf("inproc adapter: node %s already exists", cfg.ID)
+	}
+
+	n := &inProcNode{cfg: cfg}
+	a.nodes[cfg.ID] = n
+	return n, nil
+}
+
+type inProcNode struct {
+	cfg  *NodeConfig
+	node *dot.Node
+	errc chan error
+}
+
+func (n *inProcNode) Config() *NodeConfig {
+	return n.cfg
+}
+
+func (n *inProcNode) Start() error {
+	dotCfg := &dot.Config{}
+	dotCfg.Global.Name = n.cfg.ID
+	dotCfg.Global.BasePath = n.cfg.BasePath
+	dotCfg.Network.Port = uint32(n.cfg.P2PPort)
+	dotCfg.Network.Bootnodes = []string{}
+	if n.cfg.Bootnodes != "" {
+		dotCfg.Network.Bootnodes = []string{n.cfg.Bootnodes}
+	}
+	// NoMDNS keeps discovery limited to Connect()/explicit bootnodes so
+	// that in-proc networks stay deterministic under test.
+	dotCfg.Network.NoMDNS = true
+
+	node, err := dot.NewNode(dotCfg, n.cfg.BasePath)
+	if err != nil {
+		return fmt.Errorf("failed to create in-proc node %s: %w", n.cfg.ID, err)
+	}
+	n.node = node
+
+	n.errc = make(chan error, 1)
+	go func() {
+		n.errc <- node.Start()
+	}()
+
+	return nil
+}
+
+func (n *inProcNode) Stop() error {
+	if n.node == nil {
+		return nil
+	}
+	return n.node.Stop()
+}
+
+func (n *inProcNode) Wait() error {
+	if n.errc == nil {
+		return fmt.Errorf("node %s was never started", n.cfg.ID)
+	}
+	return <-n.errc
+}
+
+func (n *inProcNode) PeerID() (string, error) {
+	if n.node == nil {
+		return "", fmt.Errorf("node %s was never started", n.cfg.ID)
+	}
+	return n.node.NetworkState().PeerID, nil
+}
+
+func (n *inProcNode) Multiaddr() (string, error) {
+	peerID, err := n.PeerID()
+	if err != nil {
+		return "", err
+	}
+	return "/ip4/127.0.0.1/tcp/" + strconv.Itoa(n.cfg.P2PPort) + "/p2p/" + peerID, nil
+}
@@ -0,0 +1,189 @@
+package simulation
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// connectPeerRetries is the number of times Connect will poll
+// system_networkState on nodeA while waiting for it to discover nodeB.
+const connectPeerRetries = 36
+
+// Network tracks a set of nodes created through a single NodeAdapter and
+// lets callers script their topology (Connect/Disconnect) instead of
+// hard-coding a star shape at startup.
+type Network struct {
+	Adapter NodeAdapter
+
+	mu    sync.Mutex
+	nodes map[string]Node
+	order []string
+}
+
+// NewNetwork creates an empty Network backed by adapter.
+func NewNetwork(adapter NodeAdapter) *Network {
+	return &Network{
+		Adapter: adapter,
+		nodes:   make(map[string]Node),
+	}
+}
+
+// NewNode creates a node via the network's adapter and starts tracking it,
+// without starting it yet.
+func (n *Network) NewNode(cfg *NodeConfig) (Node, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, exists := n.nodes[cfg.ID]; exists {
+		return nil, fmt.Errorf("node %s already exists in network", cfg.ID)
+	}
+
+	node, err := n.Adapter.NewNode(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	n.nodes[cfg.ID] = node
+	n.order = append(n.order, cfg.ID)
+	return node, nil
+}
+
+// Get returns the node with the given ID, if it exists.
+func (n *Network) Get(id string) (Node, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	node, ok := n.nodes[id]
+	return node, ok
+}
+
+// Nodes returns all nodes in the order they were added.
+func (n *Network) Nodes() []Node {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	nodes := make([]Node, 0, len(n.order))
+	for _, id := range n.order {
+		nodes = append(nodes, n.nodes[id])
+	}
+	return nodes
+}
+
+// StartAll starts every node in the network concurrently and waits for all
+// of them to report running before returning.
+func (n *Network) StartAll() error {
+	nodes := n.Nodes()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(nodes))
+	wg.Add(len(nodes))
+
+	for i, node := range nodes {
+		go func(i int, node Node) {
+			defer wg.Done()
+			errs[i] = node.Start()
+		}(i, node)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Connect dials nodeB's multiaddr from nodeA, and blocks until nodeA
+// reports nodeB as a known peer via system_networkState.
+func (n *Network) Connect(idA, idB string) error {
+	nodeA, ok := n.Get(idA)
+	if !ok {
+		return fmt.Errorf("unknown node %s", idA)
+	}
+	nodeB, ok := n.Get(idB)
+	if !ok {
+		return fmt.Errorf("unknown node %s", idB)
+	}
+
+	addrB, err := nodeB.Multiaddr()
+	if err != nil {
+		return fmt.Errorf("failed to get multiaddr for %s: %w", idB, err)
+	}
+
+	_, err = PostRPC("system_addReservedPeer", nodeA.Config().RPCAddr(), `["`+addrB+`"]`)
+	if err != nil {
+		return fmt.Errorf("failed to connect %s to %s: %w", idA, idB, err)
+	}
+
+	peerIDB, err := nodeB.PeerID()
+	if err != nil {
+		return fmt.Errorf("failed to get peer ID for %s: %w", idB, err)
+	}
+
+	for i := 0; i < connectPeerRetries; i++ {
+		peers, err := queryPeers(nodeA.Config().RPCAddr())
+		if err == nil {
+			for _, p := range peers {
+				if p == peerIDB {
+					return nil
+				}
+			}
+		}
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("timed out waiting for %s to discover %s as a peer", idA, idB)
+}
+
+// Disconnect removes nodeB as a reserved peer of nodeA.
+func (n *Network) Disconnect(idA, idB string) error {
+	nodeA, ok := n.Get(idA)
+	if !ok {
+		return fmt.Errorf("unknown node %s", idA)
+	}
+	nodeB, ok := n.Get(idB)
+	if !ok {
+		return fmt.Errorf("unknown node %s", idB)
+	}
+
+	peerID, err := nodeB.PeerID()
+	if err != nil {
+		return fmt.Errorf("failed to get peer ID for %s: %w", idB, err)
+	}
+
+	_, err = PostRPC("system_removeReservedPeer", nodeA.Config().RPCAddr(), `["`+peerID+`"]`)
+	if err != nil {
+		return fmt.Errorf("failed to disconnect %s from %s: %w", idA, idB, err)
+	}
+
+	return nil
+}
+
+// Shutdown stops every node in the network. It collects and returns the
+// first error encountered, but always attempts to stop every node.
+func (n *Network) Shutdown() error {
+	var firstErr error
+	for _, node := range n.Nodes() {
+		if err := node.Stop(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WaitForSignal blocks until the process receives SIGINT or SIGTERM, then
+// tears down the network and returns.
+func (n *Network) WaitForSignal() {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	<-sigc
+	signal.Stop(sigc)
+
+	if err := n.Shutdown(); err != nil {
+		fmt.Fprintf(os.Stderr, "error during network shutdown: %s\n", err)
+	}
+}
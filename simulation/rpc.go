@@ -0,0 +1,132 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules"
+)
+
+var (
+	dialTimeout       = 60 * time.Second
+	httpClientTimeout = 120 * time.Second
+
+	transport = &http.Transport{
+		Dial: (&net.Dialer{
+			Timeout: dialTimeout,
+		}).Dial,
+	}
+	httpClient = &http.Client{
+		Transport: transport,
+		Timeout:   httpClientTimeout,
+	}
+)
+
+// ServerResponse wraps a JSON-RPC response.
+type ServerResponse struct {
+	// JSON-RPC Version
+	Version string `json:"jsonrpc"`
+	// Resulting values
+	Result json.RawMessage `json:"result"`
+	// Any generated errors
+	Error *RPCError `json:"error"`
+	// Request id
+	ID *json.RawMessage `json:"id"`
+}
+
+// ErrCode is an int type used for the rpc error codes.
+type ErrCode int
+
+// RPCError holds the error message and code returned by a JSON-RPC call.
+type RPCError struct {
+	Message   string                 `json:"message"`
+	ErrorCode ErrCode                `json:"code"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// PostRPC posts a JSON-RPC request for method with the given raw params to
+// host and returns the raw response body.
+func PostRPC(method, host, params string) ([]byte, error) {
+	data := []byte(`{"jsonrpc":"2.0","method":"` + method + `","params":` + params + `,"id":1}`)
+
+	r, err := http.NewRequest("POST", host, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(r)
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code not OK")
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// DecodeRPC decodes a raw JSON-RPC response body into target, returning an
+// error if the response itself carried one.
+func DecodeRPC(body []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	var response ServerResponse
+	err := decoder.Decode(&response)
+	if err != nil {
+		return err
+	}
+
+	if response.Error != nil {
+		return errors.New(response.Error.Message)
+	}
+
+	decoder = json.NewDecoder(bytes.NewReader(response.Result))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+// queryPeerID fetches the libp2p peer ID of the node listening at endpoint.
+func queryPeerID(endpoint string) (string, error) {
+	respBody, err := PostRPC("system_networkState", endpoint, "[]")
+	if err != nil {
+		return "", err
+	}
+
+	networkState := new(modules.SystemNetworkStateResponse)
+	err = DecodeRPC(respBody, &networkState)
+	if err != nil {
+		return "", err
+	}
+
+	return networkState.NetworkState.PeerID, nil
+}
+
+// queryPeers fetches the libp2p peer IDs the node listening at endpoint
+// currently knows about.
+func queryPeers(endpoint string) ([]string, error) {
+	respBody, err := PostRPC("system_networkState", endpoint, "[]")
+	if err != nil {
+		return nil, err
+	}
+
+	networkState := new(modules.SystemNetworkStateResponse)
+	err = DecodeRPC(respBody, &networkState)
+	if err != nil {
+		return nil, err
+	}
+
+	return networkState.NetworkState.Peers, nil
+}
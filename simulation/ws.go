@@ -0,0 +1,326 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// subscriptionBufferSize is sized to match realistic subscription load: a
+// network of nodes each streaming storage-change notifications can easily
+// produce tens of thousands of pending messages between reads.
+const subscriptionBufferSize = 20000
+
+// reconnectBackoff is how long WSClient waits between reconnect attempts.
+const reconnectBackoff = time.Second
+
+type wsRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+type wsResponse struct {
+	ID     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+	Method string          `json:"method"`
+	Params struct {
+		Result       json.RawMessage `json:"result"`
+		Subscription json.RawMessage `json:"subscription"`
+	} `json:"params"`
+}
+
+// WSClient multiplexes any number of subscriptions over a single websocket
+// connection to a node's RPC server, automatically reconnecting (and
+// re-issuing every active subscription) if the connection drops.
+type WSClient struct {
+	addr string
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	nextID  int
+	pending map[int]chan wsResponse
+	subs    map[string]func(json.RawMessage)
+	resubs  []func() error
+
+	// writeMu serializes writes to conn: gorilla/websocket forbids
+	// concurrent writers, but concurrent call()s and handleDisconnect's
+	// resubs can all write to the same connection.
+	writeMu sync.Mutex
+
+	notifications chan wsResponse
+	closed        chan struct{}
+}
+
+// NewWSClient dials addr (a ws:// or http:// URL, which is rewritten to
+// ws://) and starts the background read/dispatch loop.
+func NewWSClient(addr string) (*WSClient, error) {
+	c := &WSClient{
+		addr:          toWSURL(addr),
+		pending:       make(map[int]chan wsResponse),
+		subs:          make(map[string]func(json.RawMessage)),
+		notifications: make(chan wsResponse, subscriptionBufferSize),
+		closed:        make(chan struct{}),
+	}
+
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+
+	go c.readLoop()
+	go c.dispatchLoop()
+
+	return c, nil
+}
+
+func toWSURL(addr string) string {
+	if strings.HasPrefix(addr, "http://") {
+		return "ws://" + strings.TrimPrefix(addr, "http://")
+	}
+	if strings.HasPrefix(addr, "https://") {
+		return "wss://" + strings.TrimPrefix(addr, "https://")
+	}
+	return addr
+}
+
+func (c *WSClient) connect() error {
+	u, err := url.Parse(c.addr)
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+	return nil
+}
+
+// Close shuts the client down, closing the underlying connection.
+func (c *WSClient) Close() error {
+	close(c.closed)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// call issues method(params) and blocks for the matching response.
+func (c *WSClient) call(method string, params json.RawMessage) (wsResponse, error) {
+	c.mu.Lock()
+	id := c.nextID
+	c.nextID++
+	replyc := make(chan wsResponse, 1)
+	c.pending[id] = replyc
+	conn := c.conn
+	c.mu.Unlock()
+
+	req := wsRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return wsResponse{}, err
+	}
+
+	c.writeMu.Lock()
+	err = conn.WriteMessage(websocket.TextMessage, data)
+	c.writeMu.Unlock()
+	if err != nil {
+		return wsResponse{}, err
+	}
+
+	resp := <-replyc
+	if resp.Error != nil {
+		return resp, fmt.Errorf(resp.Error.Message)
+	}
+	return resp, nil
+}
+
+// Subscribe issues method(params) (e.g. "author_submitAndWatchExtrinsic" or
+// "state_subscribeStorage") and calls handler with every notification
+// delivered for the resulting subscription, until Unsubscribe is called.
+// The subscription is automatically re-issued if the connection reconnects.
+func (c *WSClient) Subscribe(method, params string, handler func(json.RawMessage)) (string, error) {
+	// subID is captured by resub, which both the initial call below and
+	// every later reconnect re-run; the server hands back a fresh
+	// subscription ID each time, so resub retires the previous one under
+	// the same lock it installs the new one.
+	var subID string
+
+	resub := func() error {
+		resp, err := c.call(method, json.RawMessage(params))
+		if err != nil {
+			return err
+		}
+
+		var newID string
+		if err := json.Unmarshal(resp.Result, &newID); err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		delete(c.subs, subID)
+		c.subs[newID] = handler
+		subID = newID
+		c.mu.Unlock()
+		return nil
+	}
+
+	if err := resub(); err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.resubs = append(c.resubs, resub)
+	id := subID
+	c.mu.Unlock()
+
+	return id, nil
+}
+
+// Unsubscribe stops delivering notifications for subID.
+func (c *WSClient) Unsubscribe(unsubscribeMethod, subID string) error {
+	c.mu.Lock()
+	delete(c.subs, subID)
+	c.mu.Unlock()
+
+	_, err := c.call(unsubscribeMethod, json.RawMessage(`["`+subID+`"]`))
+	return err
+}
+
+func (c *WSClient) readLoop() {
+	for {
+		select {
+		case <-c.closed:
+			return
+		default:
+		}
+
+		c.mu.Lock()
+		conn := c.conn
+		c.mu.Unlock()
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if c.isClosed() {
+				return
+			}
+			// handleDisconnect blocks on resub() -> call(), which can only
+			// be answered by a readLoop reading the reconnected socket.
+			// Hand off to a fresh goroutine and let this one exit instead
+			// of parking the only reader inside handleDisconnect.
+			go c.handleDisconnect()
+			return
+		}
+
+		var resp wsResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			continue
+		}
+
+		select {
+		case c.notifications <- resp:
+		default:
+			// notification buffer is full; drop rather than block the
+			// socket read loop.
+		}
+	}
+}
+
+func (c *WSClient) dispatchLoop() {
+	for {
+		select {
+		case <-c.closed:
+			return
+		case resp := <-c.notifications:
+			if resp.ID != nil {
+				c.mu.Lock()
+				replyc, ok := c.pending[*resp.ID]
+				if ok {
+					delete(c.pending, *resp.ID)
+				}
+				c.mu.Unlock()
+				if ok {
+					replyc <- resp
+				}
+				continue
+			}
+
+			var subID string
+			if err := json.Unmarshal(resp.Params.Subscription, &subID); err != nil {
+				continue
+			}
+
+			c.mu.Lock()
+			handler, ok := c.subs[subID]
+			c.mu.Unlock()
+			if ok {
+				handler(resp.Params.Result)
+			}
+		}
+	}
+}
+
+func (c *WSClient) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// handleDisconnect reconnects and re-issues every active subscription. It
+// runs on its own goroutine (never on readLoop's), since re-issuing a
+// subscription calls call(), which blocks for a reply that only a running
+// readLoop can deliver.
+func (c *WSClient) handleDisconnect() {
+	for {
+		if c.isClosed() {
+			return
+		}
+		if err := c.connect(); err == nil {
+			break
+		}
+		time.Sleep(reconnectBackoff)
+	}
+
+	// Start reading the new connection before resubscribing, so call()'s
+	// replies have somewhere to land.
+	go c.readLoop()
+
+	c.mu.Lock()
+	resubs := append([]func() error{}, c.resubs...)
+	c.mu.Unlock()
+
+	for _, resub := range resubs {
+		_ = resub()
+	}
+}
+
+// WSSubscribe is a convenience wrapper for one-off subscriptions: it dials
+// addr, subscribes to method(params), and returns the client so callers can
+// Close() it once they're done. It's the WebSocket counterpart to PostRPC.
+func WSSubscribe(addr, method, params string, handler func(json.RawMessage)) (*WSClient, error) {
+	c, err := NewWSClient(addr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.Subscribe(method, params, handler); err != nil {
+		_ = c.Close()
+		return nil, err
+	}
+	return c, nil
+}
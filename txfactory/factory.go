@@ -0,0 +1,55 @@
+// Package txfactory builds encoded extrinsics for the demo's --tx-kind
+// flag. Each Factory knows how to turn a Params into wire bytes for one
+// kind of call, so main.go no longer has to hardcode
+// extrinsic.NewStorageChangeExt.
+//
+// balance-transfer, set-session-key and sudo are deliberately not
+// implemented: gossamer's lib/runtime/extrinsic package this demo links
+// against exposes only NewStorageChangeExt, so there is no real
+// constructor to build their SCALE encoding against, and hand-rolling one
+// would just be guessing at a wire format this tree can't verify. Those
+// three kinds are descoped rather than half-built; StorageChange and
+// RawFile are the only supported Factory kinds until gossamer grows the
+// missing constructors.
+package txfactory
+
+import "fmt"
+
+// Params carries every flag a Factory might need. A given Factory only
+// reads the fields relevant to the kind of extrinsic it builds.
+type Params struct {
+	// Key/Value are used by StorageChange.
+	Key   []byte
+	Value []byte
+
+	// File is used by RawFile.
+	File string
+}
+
+// Factory builds and SCALE-encodes a single extrinsic from Params.
+type Factory interface {
+	// Name identifies the factory; it's also the --tx-kind flag value.
+	Name() string
+	Build(p Params) ([]byte, error)
+}
+
+// Registry holds every built-in Factory, keyed by Name().
+var Registry = map[string]Factory{}
+
+func register(f Factory) {
+	Registry[f.Name()] = f
+}
+
+func init() {
+	register(StorageChange{})
+	register(RawFile{})
+}
+
+// Get looks up a registered Factory by --tx-kind name.
+func Get(kind string) (Factory, error) {
+	f, ok := Registry[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown tx kind %q", kind)
+	}
+	return f, nil
+}
@@ -0,0 +1,24 @@
+package txfactory
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+// RawFile reads an already-encoded extrinsic from disk verbatim. It's the
+// escape hatch for extrinsics built by tooling outside this repo (e.g. a
+// runtime's own test vectors).
+type RawFile struct{}
+
+// Name implements Factory.
+func (RawFile) Name() string {
+	return "raw-file"
+}
+
+// Build implements Factory.
+func (RawFile) Build(p Params) ([]byte, error) {
+	if p.File == "" {
+		return nil, fmt.Errorf("raw-file: --file is required")
+	}
+	return ioutil.ReadFile(p.File)
+}
@@ -0,0 +1,21 @@
+package txfactory
+
+import (
+	"github.com/ChainSafe/gossamer/lib/common/optional"
+	"github.com/ChainSafe/gossamer/lib/runtime/extrinsic"
+)
+
+// StorageChange builds a raw storage-change extrinsic, the kind the demo
+// has always submitted (extrinsic.NewStorageChangeExt).
+type StorageChange struct{}
+
+// Name implements Factory.
+func (StorageChange) Name() string {
+	return "storage-change"
+}
+
+// Build implements Factory.
+func (StorageChange) Build(p Params) ([]byte, error) {
+	ext := extrinsic.NewStorageChangeExt(p.Key, optional.NewBytes(true, p.Value))
+	return ext.Encode()
+}